@@ -0,0 +1,270 @@
+// Package file provides tagconfig.TagValueGetter implementations backed by
+// JSON, YAML, TOML, and .env files, turning tagconfig from an env-only
+// helper into a general config-loading library. Each Getter resolves
+// dotted tag keys (e.g. "meta.activity") into nested lookups against the
+// parsed document, and pairs naturally with tagconfig.Layered - file
+// defaults overridden by env overridden by a remote KV store.
+package file
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// defaultTagName is the struct tag a Getter looks for unless WithTagName
+// is supplied.
+const defaultTagName = "tagconfig"
+
+// Getter is a tagconfig.TagValueGetter backed by a parsed document tree.
+// It is returned by NewJSON, NewYAML, NewTOML, and NewDotenv (and their
+// *Reader variants) rather than constructed directly.
+type Getter struct {
+	tagName   string
+	expandEnv bool
+	tree      map[string]interface{}
+}
+
+// Option configures a Getter.
+type Option func(*Getter)
+
+// WithTagName overrides the struct tag a Getter looks for. The default is
+// "tagconfig".
+func WithTagName(tag string) Option {
+	return func(g *Getter) { g.tagName = tag }
+}
+
+// WithEnvExpansion expands ${VAR} references found in values read from the
+// document against the process environment.
+func WithEnvExpansion() Option {
+	return func(g *Getter) { g.expandEnv = true }
+}
+
+func newGetter(tree map[string]interface{}, opts []Option) *Getter {
+	g := &Getter{tagName: defaultTagName, tree: tree}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// TagName implements tagconfig.TagNameGetter.
+func (g *Getter) TagName() string {
+	return g.tagName
+}
+
+// Get implements tagconfig.TagValueGetter. It first looks for key as a
+// literal top-level entry - the shape a flat .env document takes - then
+// falls back to descending the parsed document one dot-separated segment
+// of key at a time, returning "" if any segment is missing or isn't itself
+// a nested document.
+func (g *Getter) Get(key string, _ reflect.StructField) string {
+	if node, ok := g.tree[key]; ok {
+		return g.format(node)
+	}
+
+	var node interface{} = g.tree
+	for _, part := range strings.Split(key, ".") {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		node, ok = m[part]
+		if !ok {
+			return ""
+		}
+	}
+	if node == nil {
+		return ""
+	}
+	return g.format(node)
+}
+
+// format renders a parsed document node as the string tagconfig.Process
+// expects. A list node is comma-joined so it lines up with processField's
+// reflect.Slice handling, and a map node is rendered as comma-separated
+// key:value pairs so it lines up with processField's reflect.Map handling
+// (see tagconfig's map-field support). Anything else is rendered with its
+// default string conversion.
+func (g *Getter) format(node interface{}) string {
+	switch v := node.(type) {
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, e := range v {
+			parts[i] = g.format(e)
+		}
+		return strings.Join(parts, ",")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = k + ":" + g.format(v[k])
+		}
+		return strings.Join(parts, ",")
+	case float64:
+		// encoding/json decodes every JSON number into float64, and %v
+		// switches a whole-number float to scientific notation once it
+		// reaches 1e6 (e.g. 1048576 -> "1.048576e+06"), which then fails
+		// processField's strconv.ParseInt. FormatFloat with 'f' keeps
+		// whole numbers in plain decimal; non-whole numbers still render
+		// the same as %v would.
+		return g.expand(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return g.expand(fmt.Sprintf("%v", v))
+	}
+}
+
+// expand applies ${VAR} environment expansion to value when the Getter was
+// built with WithEnvExpansion, and returns value unchanged otherwise.
+func (g *Getter) expand(value string) string {
+	if g.expandEnv {
+		return os.Expand(value, os.Getenv)
+	}
+	return value
+}
+
+// NewJSON builds a Getter from the JSON document at path.
+func NewJSON(path string, opts ...Option) (*Getter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewJSONReader(f, opts...)
+}
+
+// NewJSONReader builds a Getter from a JSON document read from r.
+func NewJSONReader(r io.Reader, opts ...Option) (*Getter, error) {
+	var tree map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&tree); err != nil {
+		return nil, err
+	}
+	return newGetter(tree, opts), nil
+}
+
+// NewYAML builds a Getter from the YAML document at path.
+func NewYAML(path string, opts ...Option) (*Getter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewYAMLReader(f, opts...)
+}
+
+// NewYAMLReader builds a Getter from a YAML document read from r.
+func NewYAMLReader(r io.Reader, opts ...Option) (*Getter, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	tree, _ := normalizeYAML(doc).(map[string]interface{})
+	return newGetter(tree, opts), nil
+}
+
+// NewTOML builds a Getter from the TOML document at path.
+func NewTOML(path string, opts ...Option) (*Getter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewTOMLReader(f, opts...)
+}
+
+// NewTOMLReader builds a Getter from a TOML document read from r.
+func NewTOMLReader(r io.Reader, opts ...Option) (*Getter, error) {
+	var tree map[string]interface{}
+	if _, err := toml.DecodeReader(r, &tree); err != nil {
+		return nil, err
+	}
+	return newGetter(tree, opts), nil
+}
+
+// NewDotenv builds a Getter from the .env file at path. Dotenv files are
+// flat KEY=VALUE pairs, so a dotted tag key must match a line's key
+// exactly rather than being resolved as a nested path.
+func NewDotenv(path string, opts ...Option) (*Getter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewDotenvReader(f, opts...)
+}
+
+// NewDotenvReader builds a Getter from a .env document read from r.
+func NewDotenvReader(r io.Reader, opts ...Option) (*Getter, error) {
+	tree := make(map[string]interface{})
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(kv[0])
+		value := strings.Trim(strings.TrimSpace(kv[1]), `"'`)
+		tree[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return newGetter(tree, opts), nil
+}
+
+// normalizeYAML recursively converts the map[interface{}]interface{} nodes
+// produced by gopkg.in/yaml.v2 into map[string]interface{}, so Get's
+// dotted traversal works the same way it does for JSON and TOML documents.
+func normalizeYAML(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, vv := range val {
+			m[fmt.Sprintf("%v", k)] = normalizeYAML(vv)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, vv := range val {
+			m[k] = normalizeYAML(vv)
+		}
+		return m
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, vv := range val {
+			out[i] = normalizeYAML(vv)
+		}
+		return out
+	default:
+		return val
+	}
+}
@@ -0,0 +1,78 @@
+package file_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/Experticity/tagconfig/file"
+	"github.com/stretchr/testify/assert"
+)
+
+var testField = reflect.TypeOf(struct {
+	X string
+}{}).Field(0)
+
+func TestJSONGetterNestedKey(t *testing.T) {
+	g, err := NewJSONReader(strings.NewReader(`{"meta":{"activity":"bowling"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "bowling", g.Get("meta.activity", testField))
+	assert.Equal(t, "", g.Get("meta.missing", testField))
+}
+
+func TestYAMLGetterNestedKey(t *testing.T) {
+	g, err := NewYAMLReader(strings.NewReader("meta:\n  activity: bowling\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bowling", g.Get("meta.activity", testField))
+}
+
+func TestTOMLGetterNestedKey(t *testing.T) {
+	g, err := NewTOMLReader(strings.NewReader("[meta]\nactivity = \"bowling\"\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bowling", g.Get("meta.activity", testField))
+}
+
+func TestDotenvGetterExactKey(t *testing.T) {
+	g, err := NewDotenvReader(strings.NewReader("meta.activity=bowling\n# comment\nDB_HOST=\"localhost\"\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bowling", g.Get("meta.activity", testField))
+	assert.Equal(t, "localhost", g.Get("DB_HOST", testField))
+}
+
+func TestGetterEnvExpansion(t *testing.T) {
+	t.Setenv("TAGCONFIG_TEST_HOST", "example.com")
+
+	g, err := NewJSONReader(strings.NewReader(`{"host":"${TAGCONFIG_TEST_HOST}"}`), WithEnvExpansion())
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", g.Get("host", testField))
+}
+
+func TestJSONGetterListValue(t *testing.T) {
+	g, err := NewJSONReader(strings.NewReader(`{"colors":["red","green","blue"]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "red,green,blue", g.Get("colors", testField))
+}
+
+func TestJSONGetterMapValue(t *testing.T) {
+	g, err := NewJSONReader(strings.NewReader(`{"labels":{"env":"prod","region":"us"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "env:prod,region:us", g.Get("labels", testField))
+}
+
+func TestJSONGetterLargeIntegerValue(t *testing.T) {
+	g, err := NewJSONReader(strings.NewReader(`{"max_bytes":1048576}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "1048576", g.Get("max_bytes", testField))
+}
+
+func TestYAMLGetterMapValue(t *testing.T) {
+	g, err := NewYAMLReader(strings.NewReader("labels:\n  env: prod\n  region: us\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "env:prod,region:us", g.Get("labels", testField))
+}
+
+func TestGetterTagName(t *testing.T) {
+	g, err := NewJSONReader(strings.NewReader(`{}`), WithTagName("cfg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "cfg", g.TagName())
+}
@@ -0,0 +1,202 @@
+package tagconfig
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Layered is a TagValueGetter that wraps an ordered list of TagValueGetters
+// and returns the first non-empty Get result. This lets callers compose
+// sources such as remote KV (Consul/etcd), env, a config file and defaults
+// under a single Process call; getters are listed in that order, since
+// earlier getters take precedence.
+type Layered struct {
+	Getters []TagValueGetter
+}
+
+// NewLayered builds a Layered TagValueGetter from getters, in precedence
+// order; the first getter to return a non-empty value for a key wins.
+func NewLayered(getters ...TagValueGetter) *Layered {
+	return &Layered{Getters: getters}
+}
+
+// TagName returns the tag name of the first wrapped getter. All getters in
+// a Layered are expected to agree on the tag name.
+func (l *Layered) TagName() string {
+	if len(l.Getters) == 0 {
+		return ""
+	}
+	return l.Getters[0].TagName()
+}
+
+// Get returns the first non-empty value found across the wrapped getters,
+// in order.
+func (l *Layered) Get(key string, t reflect.StructField) string {
+	for _, g := range l.Getters {
+		if v := g.Get(key, t); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Watcher is implemented by a TagValueGetter that can notify callers when a
+// new value is available for a key. Getters backed by a remote store such
+// as Consul or etcd are expected to implement this so ProcessAndWatch can
+// keep a spec up to date as values change.
+type Watcher interface {
+	Watch(key string, cb func(newValue string))
+}
+
+// WatchHandle is returned by ProcessAndWatch. A watcher callback may update
+// spec from its own goroutine at any time, so callers must hold a read
+// lock - via RLock/RUnlock - around any read of spec to avoid racing with
+// an in-flight update. Stop makes every registered callback ignore later
+// values.
+type WatchHandle struct {
+	mu    *sync.RWMutex
+	stops []func()
+
+	errMu   sync.Mutex
+	lastErr error
+}
+
+// LastError returns the most recent error encountered while applying a
+// watched update to spec - for example a new value that failed to parse
+// into the field's type - or nil if every update so far has applied
+// cleanly. A failed update leaves the field at its previous value. The
+// result is not cleared on read, so a caller polling for new failures
+// should track whether it differs from the last call.
+func (h *WatchHandle) LastError() error {
+	h.errMu.Lock()
+	defer h.errMu.Unlock()
+	return h.lastErr
+}
+
+func (h *WatchHandle) setLastError(err error) {
+	h.errMu.Lock()
+	defer h.errMu.Unlock()
+	h.lastErr = err
+}
+
+// RLock acquires the handle's read lock. Hold it for the duration of any
+// read of the spec passed to ProcessAndWatch.
+func (h *WatchHandle) RLock() {
+	h.mu.RLock()
+}
+
+// RUnlock releases the handle's read lock.
+func (h *WatchHandle) RUnlock() {
+	h.mu.RUnlock()
+}
+
+// Stop makes every callback ProcessAndWatch registered ignore later values,
+// so spec no longer changes. The callbacks stay registered with their
+// getters; Watcher has no way to cancel one.
+func (h *WatchHandle) Stop() {
+	for _, s := range h.stops {
+		s()
+	}
+}
+
+// ProcessAndWatch runs Process once against v and spec, then subscribes to
+// every tagged field on any getter that implements Watcher (recursing into
+// the wrapped getters of a Layered). When a new value arrives for a watched
+// key, the corresponding struct field is re-processed under the returned
+// handle's write lock, so callers reading spec concurrently should hold
+// its read lock (see WatchHandle). An update that fails to parse leaves
+// the field unchanged and is recorded on the handle - see LastError.
+func ProcessAndWatch(v TagValueGetter, spec interface{}) (*WatchHandle, error) {
+	if err := Process(v, spec); err != nil {
+		return nil, err
+	}
+
+	h := &WatchHandle{mu: &sync.RWMutex{}}
+	h.stops = watchFields(v, spec, h, "")
+
+	return h, nil
+}
+
+func watchersFrom(v TagValueGetter) []Watcher {
+	if l, ok := v.(*Layered); ok {
+		var out []Watcher
+		for _, g := range l.Getters {
+			out = append(out, watchersFrom(g)...)
+		}
+		return out
+	}
+	if w, ok := v.(Watcher); ok {
+		return []Watcher{w}
+	}
+	return nil
+}
+
+func watchFields(v TagValueGetter, spec interface{}, h *WatchHandle, prefix string) []func() {
+	watchers := watchersFrom(v)
+	if len(watchers) == 0 {
+		return nil
+	}
+
+	s := reflect.ValueOf(spec).Elem()
+	typeOfSpec := s.Type()
+
+	var stops []func()
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Field(i)
+		ft := typeOfSpec.Field(i)
+		if !f.CanSet() || ft.Tag.Get("ignored") == "true" {
+			continue
+		}
+
+		if ft.Anonymous && f.Kind() == reflect.Struct {
+			stops = append(stops, watchFields(v, f.Addr().Interface(), h, prefix)...)
+			continue
+		}
+
+		// Mirror process's recursion into named (non-anonymous) struct
+		// fields, so a watcher subscribes to the leaf keys (e.g.
+		// "db.host") rather than the struct's own tag key.
+		if !ft.Anonymous && f.Kind() == reflect.Struct && f.CanAddr() && decoderFrom(f) == nil {
+			ownKey := ft.Tag.Get(v.TagName())
+			childPrefix := prefix
+			if ft.Tag.Get("flatten") != "true" {
+				childPrefix = joinKey(prefix, ownKey)
+			}
+			stops = append(stops, watchFields(v, f.Addr().Interface(), h, childPrefix)...)
+			continue
+		}
+
+		key := ft.Tag.Get(v.TagName())
+		if key == "" {
+			continue
+		}
+		key = joinKey(prefix, key)
+
+		field := f
+		for _, w := range watchers {
+			var stopped int32
+			w.Watch(key, func(newValue string) {
+				if atomic.LoadInt32(&stopped) != 0 {
+					return
+				}
+				h.mu.Lock()
+				// Parse into a copy so a value that fails to parse cannot
+				// leave field changed (e.g. a nil pointer newly allocated).
+				tmp := reflect.New(field.Type()).Elem()
+				tmp.Set(field)
+				err := processField(newValue, tmp)
+				if err == nil {
+					field.Set(tmp)
+				}
+				h.mu.Unlock()
+				if err != nil {
+					h.setLastError(err)
+				}
+			})
+			stops = append(stops, func() { atomic.StoreInt32(&stopped, 1) })
+		}
+	}
+
+	return stops
+}
@@ -0,0 +1,142 @@
+package tagconfig_test
+
+import (
+	"testing"
+
+	. "github.com/Experticity/tagconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredPrecedence(t *testing.T) {
+	defaults := &MockGetter{Values: map[string]string{"handle": "zero.cool", "crash.count": "1"}}
+	env := &MockGetter{Values: map[string]string{"crash.count": "1507"}}
+
+	l := NewLayered(env, defaults)
+
+	spec := &Specification{}
+	err := Process(l, spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1507, spec.SystemsCrashCount)
+	assert.Equal(t, "zero.cool", spec.Handle)
+}
+
+type watchingGetter struct {
+	MockGetter
+	subs map[string]func(string)
+}
+
+func newWatchingGetter() *watchingGetter {
+	return &watchingGetter{
+		MockGetter: MockGetter{Values: make(map[string]string)},
+		subs:       make(map[string]func(string)),
+	}
+}
+
+func (w *watchingGetter) Watch(key string, cb func(newValue string)) {
+	w.subs[key] = cb
+}
+
+func (w *watchingGetter) publish(key, value string) {
+	if cb, ok := w.subs[key]; ok {
+		cb(value)
+	}
+}
+
+// publishAsync fires the callback from its own goroutine, the way a real
+// etcd/Consul watcher would, so a consumer must synchronize with the
+// handle returned by ProcessAndWatch rather than assume updates land
+// between calls on the test's own goroutine.
+func (w *watchingGetter) publishAsync(key, value string) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		w.publish(key, value)
+		close(done)
+	}()
+	return done
+}
+
+func TestProcessAndWatch(t *testing.T) {
+	wg := newWatchingGetter()
+	wg.Values["crash.count"] = "1507"
+	wg.Values["handle"] = "crash.override"
+
+	spec := &Specification{}
+
+	h, err := ProcessAndWatch(wg, spec)
+	assert.NoError(t, err)
+	defer h.Stop()
+
+	assert.Equal(t, 1507, spec.SystemsCrashCount)
+
+	wg.publish("crash.count", "2600")
+	assert.Equal(t, 2600, spec.SystemsCrashCount)
+
+	h.Stop()
+
+	wg.publish("crash.count", "9999")
+	assert.Equal(t, 2600, spec.SystemsCrashCount)
+}
+
+func TestProcessAndWatchNestedStruct(t *testing.T) {
+	wg := newWatchingGetter()
+	wg.Values["db.host"] = "localhost"
+	wg.Values["db.port"] = "5432"
+
+	spec := &SpecificationWithNested{}
+
+	h, err := ProcessAndWatch(wg, spec)
+	assert.NoError(t, err)
+	defer h.Stop()
+
+	assert.Equal(t, "localhost", spec.DB.Host)
+
+	wg.publish("db.host", "db.example.com")
+	assert.Equal(t, "db.example.com", spec.DB.Host)
+}
+
+func TestProcessAndWatchConcurrentRead(t *testing.T) {
+	wg := newWatchingGetter()
+	wg.Values["crash.count"] = "1507"
+	wg.Values["handle"] = "crash.override"
+
+	spec := &Specification{}
+
+	h, err := ProcessAndWatch(wg, spec)
+	assert.NoError(t, err)
+	defer h.Stop()
+
+	done := wg.publishAsync("crash.count", "2600")
+
+	h.RLock()
+	_ = spec.SystemsCrashCount
+	h.RUnlock()
+
+	<-done
+
+	h.RLock()
+	assert.Equal(t, 2600, spec.SystemsCrashCount)
+	h.RUnlock()
+}
+
+func TestProcessAndWatchLastError(t *testing.T) {
+	wg := newWatchingGetter()
+	wg.Values["crash.count"] = "1507"
+	wg.Values["handle"] = "crash.override"
+
+	spec := &Specification{}
+
+	h, err := ProcessAndWatch(wg, spec)
+	assert.NoError(t, err)
+	defer h.Stop()
+
+	assert.NoError(t, h.LastError())
+
+	wg.publish("crash.count", "not-a-number")
+
+	assert.Error(t, h.LastError())
+
+	h.RLock()
+	assert.Equal(t, 1507, spec.SystemsCrashCount)
+	h.RUnlock()
+}
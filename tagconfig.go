@@ -5,7 +5,9 @@ package tagconfig
 // I take no credit for the process bits, all I've changed is adding some interfaces to allow for an implementation that would
 // get environment variables, or remote key value store, or config file or or or.
 import (
+	"encoding"
 	"errors"
+	"flag"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -55,8 +57,29 @@ type TagValueSetter interface {
 	Set(key string, value interface{}, t reflect.StructField) error
 }
 
+// NestedSeparator joins a parent field's tag key with a nested struct
+// field's own tag key when Process or PopulateExternalSource descends into
+// a named (non-anonymous) struct field. The default is ".".
+var NestedSeparator = "."
+
+// joinKey prefixes key with prefix and NestedSeparator, unless either is
+// empty.
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	if key == "" {
+		return prefix
+	}
+	return prefix + NestedSeparator + key
+}
+
 // Process populates the specified struct based on the TagValueGetter implementation
 func Process(v TagValueGetter, spec interface{}) error {
+	return process(v, spec, "")
+}
+
+func process(v TagValueGetter, spec interface{}, prefix string) error {
 	s := reflect.ValueOf(spec)
 
 	if s.Kind() != reflect.Ptr {
@@ -67,36 +90,64 @@ func Process(v TagValueGetter, spec interface{}) error {
 		return ErrInvalidSpecification
 	}
 	typeOfSpec := s.Type()
+	var valErrs ValidationErrors
 	for i := 0; i < s.NumField(); i++ {
 		f := s.Field(i)
-		if !f.CanSet() || typeOfSpec.Field(i).Tag.Get("ignored") == "true" {
+		ft := typeOfSpec.Field(i)
+		if !f.CanSet() || ft.Tag.Get("ignored") == "true" {
 			continue
 		}
 
-		if typeOfSpec.Field(i).Anonymous && f.Kind() == reflect.Struct {
+		if ft.Anonymous && f.Kind() == reflect.Struct {
 			embeddedPtr := f.Addr().Interface()
-			if err := Process(v, embeddedPtr); err != nil {
+			err := process(v, embeddedPtr, prefix)
+			if nested, ok := err.(ValidationErrors); ok {
+				valErrs = append(valErrs, nested...)
+			} else if err != nil {
 				return err
 			}
 			f.Set(reflect.ValueOf(embeddedPtr).Elem())
 		}
 
+		// A named (non-anonymous) struct field recurses rather than being
+		// treated as a leaf, joining its own tag key onto prefix, unless it
+		// knows how to decode itself or opts out of prefixing via
+		// flatten:"true".
+		if !ft.Anonymous && f.Kind() == reflect.Struct && f.CanAddr() && decoderFrom(f) == nil {
+			ownKey := ft.Tag.Get(v.TagName())
+			childPrefix := prefix
+			if ft.Tag.Get("flatten") != "true" {
+				childPrefix = joinKey(prefix, ownKey)
+			}
+
+			nestedPtr := f.Addr().Interface()
+			err := process(v, nestedPtr, childPrefix)
+			if nested, ok := err.(ValidationErrors); ok {
+				valErrs = append(valErrs, nested...)
+			} else if err != nil {
+				return err
+			}
+			f.Set(reflect.ValueOf(nestedPtr).Elem())
+			continue
+		}
+
 		// Pull the key from TagValueGetter
-		key := typeOfSpec.Field(i).Tag.Get(v.TagName())
+		key := ft.Tag.Get(v.TagName())
 		if key == "" {
 			continue
 		}
+		key = joinKey(prefix, key)
 
 		// Let the TagValueGetter decide how to extract the value and pass
 		// along the structField so it can inspect potential meta data.
-		value := v.Get(key, typeOfSpec.Field(i))
+		value := v.Get(key, ft)
 
-		def := typeOfSpec.Field(i).Tag.Get("default")
+		def := ft.Tag.Get("default")
 		if def != "" && value == "" {
 			value = def
 		}
 
-		req := typeOfSpec.Field(i).Tag.Get("required")
+		req := ft.Tag.Get("required")
 		if value == "" && def == "" {
 			if req == "true" {
 				return fmt.Errorf("required key %s missing value", key)
@@ -112,6 +163,18 @@ func Process(v TagValueGetter, spec interface{}) error {
 				Value:     value,
 			}
 		}
+
+		if rules := ft.Tag.Get("validate"); rules != "" {
+			for _, rule := range strings.Split(rules, ",") {
+				if err := DefaultValidator.Validate(strings.TrimSpace(rule), key, f.Interface()); err != nil {
+					valErrs = append(valErrs, err)
+				}
+			}
+		}
+	}
+
+	if len(valErrs) > 0 {
+		return valErrs
 	}
 	return nil
 }
@@ -187,28 +250,104 @@ func processField(value string, field reflect.Value) error {
 			}
 		}
 		field.Set(sl)
+	case reflect.Map:
+		pairs := strings.Split(value, ",")
+		m := reflect.MakeMapWithSize(typ, len(pairs))
+		for _, pair := range pairs {
+			kv := strings.SplitN(pair, ":", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("invalid map entry %q, expected key:value", pair)
+			}
+
+			key := reflect.New(typ.Key()).Elem()
+			if err := processField(kv[0], key); err != nil {
+				return err
+			}
+
+			elem := reflect.New(typ.Elem()).Elem()
+			if err := processField(kv[1], elem); err != nil {
+				return err
+			}
+
+			m.SetMapIndex(key, elem)
+		}
+		field.Set(m)
 	}
 
 	return nil
 }
 
+// textUnmarshalerDecoder adapts an encoding.TextUnmarshaler to the Decoder
+// interface.
+type textUnmarshalerDecoder struct {
+	encoding.TextUnmarshaler
+}
+
+func (d textUnmarshalerDecoder) Decode(value string) error {
+	return d.UnmarshalText([]byte(value))
+}
+
+// binaryUnmarshalerDecoder adapts an encoding.BinaryUnmarshaler to the
+// Decoder interface.
+type binaryUnmarshalerDecoder struct {
+	encoding.BinaryUnmarshaler
+}
+
+func (d binaryUnmarshalerDecoder) Decode(value string) error {
+	return d.UnmarshalBinary([]byte(value))
+}
+
+// flagValueDecoder adapts a flag.Value to the Decoder interface.
+type flagValueDecoder struct {
+	flag.Value
+}
+
+func (d flagValueDecoder) Decode(value string) error {
+	return d.Set(value)
+}
+
+// decoderFrom inspects field, and its address if it can be taken, for the
+// Decoder interface and the standard library's equivalents, in order of
+// precedence: Decoder, encoding.TextUnmarshaler, encoding.BinaryUnmarshaler,
+// flag.Value.
 func decoderFrom(field reflect.Value) Decoder {
-	if field.CanInterface() {
-		dec, ok := field.Interface().(Decoder)
-		if ok {
-			return dec
-		}
+	candidates := []reflect.Value{field}
+	if field.CanAddr() {
+		candidates = append(candidates, field.Addr())
 	}
 
-	// also check if pointer-to-type implements Decoder,
-	// and we can get a pointer to our field
-	if field.CanAddr() {
-		field = field.Addr()
-		dec, ok := field.Interface().(Decoder)
-		if ok {
+	for _, c := range candidates {
+		if !c.CanInterface() {
+			continue
+		}
+		if dec, ok := c.Interface().(Decoder); ok {
 			return dec
 		}
 	}
+	for _, c := range candidates {
+		if !c.CanInterface() {
+			continue
+		}
+		if tu, ok := c.Interface().(encoding.TextUnmarshaler); ok {
+			return textUnmarshalerDecoder{tu}
+		}
+	}
+	for _, c := range candidates {
+		if !c.CanInterface() {
+			continue
+		}
+		if bu, ok := c.Interface().(encoding.BinaryUnmarshaler); ok {
+			return binaryUnmarshalerDecoder{bu}
+		}
+	}
+	for _, c := range candidates {
+		if !c.CanInterface() {
+			continue
+		}
+		if fv, ok := c.Interface().(flag.Value); ok {
+			return flagValueDecoder{fv}
+		}
+	}
 
 	return nil
 }
@@ -220,6 +359,10 @@ func decoderFrom(field reflect.Value) Decoder {
 // location, this would allow you to do so based off of how the TagValueSetter
 // has been implemented.
 func PopulateExternalSource(v TagValueSetter, spec interface{}) error {
+	return populateExternalSource(v, spec, "")
+}
+
+func populateExternalSource(v TagValueSetter, spec interface{}, prefix string) error {
 	s := reflect.ValueOf(spec)
 
 	if s.Kind() != reflect.Ptr {
@@ -236,18 +379,38 @@ func PopulateExternalSource(v TagValueSetter, spec interface{}) error {
 		f := s.Field(i)
 		ft := typeOfSpec.Field(i)
 
-		if typeOfSpec.Field(i).Anonymous && f.Kind() == reflect.Struct {
+		if ft.Anonymous && f.Kind() == reflect.Struct {
 			embeddedPtr := f.Addr().Interface()
-			if err := PopulateExternalSource(v, embeddedPtr); err != nil {
+			if err := populateExternalSource(v, embeddedPtr, prefix); err != nil {
 				return err
 			}
-		} else {
-			t := ft.Tag.Get(v.TagName())
-			if t != "" {
-				err := v.Set(t, f.Interface(), ft)
-				if err != nil {
-					return err
-				}
+			continue
+		}
+
+		// A named (non-anonymous) struct field recurses, joining its own
+		// tag key onto prefix, unless flatten:"true" opts it out of
+		// prefixing. A field that already knows how to represent itself
+		// (the same check process uses before recursing) is left as a
+		// terminal value instead, so types like time.Time are handed to
+		// Set whole rather than walked field by field.
+		if ft.PkgPath == "" && f.Kind() == reflect.Struct && f.CanAddr() && decoderFrom(f) == nil {
+			ownKey := ft.Tag.Get(v.TagName())
+			childPrefix := prefix
+			if ft.Tag.Get("flatten") != "true" {
+				childPrefix = joinKey(prefix, ownKey)
+			}
+
+			if err := populateExternalSource(v, f.Addr().Interface(), childPrefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		t := ft.Tag.Get(v.TagName())
+		if t != "" {
+			err := v.Set(joinKey(prefix, t), f.Interface(), ft)
+			if err != nil {
+				return err
 			}
 		}
 	}
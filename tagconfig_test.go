@@ -1,10 +1,12 @@
 package tagconfig_test
 
 import (
+	"net"
 	"reflect"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"sync"
 
@@ -140,6 +142,92 @@ func TestEmbeddedButIgnored(t *testing.T) {
 	assert.Equal(t, spec.Ignored.Name, "nombre")
 }
 
+type SpecificationWithMap struct {
+	Weights map[string]int `emaNgaT:"weights"`
+}
+
+func TestMapField(t *testing.T) {
+	spec := &SpecificationWithMap{}
+
+	mg := &MockGetter{Values: make(map[string]string)}
+	mg.Values["weights"] = "red:1,green:2,blue:3"
+
+	err := Process(mg, spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"red": 1, "green": 2, "blue": 3}, spec.Weights)
+}
+
+func TestMapFieldMalformed(t *testing.T) {
+	spec := &SpecificationWithMap{}
+
+	mg := &MockGetter{Values: make(map[string]string)}
+	mg.Values["weights"] = "red1,green:2"
+
+	err := Process(mg, spec)
+
+	assert.Error(t, err)
+}
+
+type SpecificationWithTextUnmarshaler struct {
+	Address net.IP `emaNgaT:"address"`
+}
+
+func TestTextUnmarshalerField(t *testing.T) {
+	spec := &SpecificationWithTextUnmarshaler{}
+
+	mg := &MockGetter{Values: make(map[string]string)}
+	mg.Values["address"] = "127.0.0.1"
+
+	err := Process(mg, spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, net.ParseIP("127.0.0.1"), spec.Address)
+}
+
+type Database struct {
+	Host string `emaNgaT:"host"`
+	Port int    `emaNgaT:"port"`
+}
+
+type SpecificationWithNested struct {
+	DB Database `emaNgaT:"db"`
+}
+
+type SpecificationWithFlattenedNested struct {
+	DB Database `emaNgaT:"db" flatten:"true"`
+}
+
+func TestNestedStruct(t *testing.T) {
+	spec := &SpecificationWithNested{}
+
+	mg := &MockGetter{Values: map[string]string{
+		"db.host": "localhost",
+		"db.port": "5432",
+	}}
+
+	err := Process(mg, spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", spec.DB.Host)
+	assert.Equal(t, 5432, spec.DB.Port)
+}
+
+func TestNestedStructFlatten(t *testing.T) {
+	spec := &SpecificationWithFlattenedNested{}
+
+	mg := &MockGetter{Values: map[string]string{
+		"host": "localhost",
+		"port": "5432",
+	}}
+
+	err := Process(mg, spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", spec.DB.Host)
+	assert.Equal(t, 5432, spec.DB.Port)
+}
+
 func TestPopulateExternalSourceSuccessful(t *testing.T) {
 	type (
 		Meta struct {
@@ -219,6 +307,62 @@ func TestPopulateExternalSourceSuccessful(t *testing.T) {
 	}
 }
 
+func TestPopulateExternalSourceNestedStruct(t *testing.T) {
+	type location struct {
+		Host string `bl:"host"`
+	}
+
+	type person struct {
+		Name string   `bl:"name"`
+		Loc  location `bl:"loc"`
+	}
+
+	p := &person{
+		Name: "The dude",
+		Loc:  location{Host: "localhost"},
+	}
+
+	m := &mockSetter{mem: map[string]string{}}
+
+	err := PopulateExternalSource(m, p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"name":     p.Name,
+		"loc.host": p.Loc.Host,
+	}, m.mem)
+}
+
+type anySetter struct {
+	tag string
+	mem map[string]interface{}
+}
+
+func (a *anySetter) TagName() string {
+	return a.tag
+}
+
+func (a *anySetter) Set(key string, value interface{}, _ reflect.StructField) error {
+	a.mem[key] = value
+	return nil
+}
+
+func TestPopulateExternalSourceTerminalStructValue(t *testing.T) {
+	type event struct {
+		When time.Time `bl:"when"`
+	}
+
+	now := time.Now()
+	e := &event{When: now}
+
+	a := &anySetter{tag: "bl", mem: map[string]interface{}{}}
+
+	err := PopulateExternalSource(a, e)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"when": now}, a.mem)
+}
+
 func TestPopulateExternalSourceError(t *testing.T) {
 	tests := []struct {
 		valFunc func() interface{}
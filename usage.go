@@ -0,0 +1,91 @@
+package tagconfig
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"text/tabwriter"
+)
+
+// usageFormat is the default per-field format used by Usage: tag key, Go
+// type, required flag, default value, and description, tab separated.
+const usageFormat = "%s\t%s\t%s\t%s\t%s\n"
+
+// Usage walks spec (descending into embedded structs) and writes a
+// tabwriter-formatted table to w describing each tagged field: its tag key,
+// Go type, whether it is required, its default value, and its
+// description:"..." struct tag. This mirrors envconfig's Usage, letting
+// operators discover configuration without reading source - particularly
+// valuable when the TagValueGetter in use is something opaque like a
+// remote KV store.
+func Usage(v TagNameGetter, spec interface{}, w io.Writer) error {
+	return Usagef(v, spec, w, usageFormat)
+}
+
+// Usagef is the same as Usage but lets callers supply their own per-field
+// format string. The format is applied with five arguments, in order: tag
+// key, Go type, required flag, default value, and description.
+func Usagef(v TagNameGetter, spec interface{}, w io.Writer, format string) error {
+	s := reflect.ValueOf(spec)
+	if s.Kind() != reflect.Ptr {
+		return ErrInvalidSpecification
+	}
+	s = s.Elem()
+	if s.Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+
+	tw := tabwriter.NewWriter(w, 1, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, format, "KEY", "TYPE", "REQUIRED", "DEFAULT", "DESCRIPTION")
+
+	if err := usageFields(v, s, tw, format, ""); err != nil {
+		return err
+	}
+
+	return tw.Flush()
+}
+
+func usageFields(v TagNameGetter, s reflect.Value, w io.Writer, format, prefix string) error {
+	typeOfSpec := s.Type()
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Field(i)
+		ft := typeOfSpec.Field(i)
+		if !f.CanSet() || ft.Tag.Get("ignored") == "true" {
+			continue
+		}
+
+		if ft.Anonymous && f.Kind() == reflect.Struct {
+			if err := usageFields(v, f, w, format, prefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Mirror process's recursion into named (non-anonymous) struct
+		// fields, so the table lists leaf keys (e.g. "db.host") instead of
+		// a single row for the struct field itself.
+		if !ft.Anonymous && f.Kind() == reflect.Struct && f.CanAddr() && decoderFrom(f) == nil {
+			ownKey := ft.Tag.Get(v.TagName())
+			childPrefix := prefix
+			if ft.Tag.Get("flatten") != "true" {
+				childPrefix = joinKey(prefix, ownKey)
+			}
+			if err := usageFields(v, f, w, format, childPrefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := ft.Tag.Get(v.TagName())
+		if key == "" {
+			continue
+		}
+		key = joinKey(prefix, key)
+
+		required, _ := strconv.ParseBool(ft.Tag.Get("required"))
+		fmt.Fprintf(w, format, key, f.Type().String(), strconv.FormatBool(required), ft.Tag.Get("default"), ft.Tag.Get("description"))
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package tagconfig_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/Experticity/tagconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsage(t *testing.T) {
+	mg := &MockGetter{Values: make(map[string]string)}
+
+	var buf bytes.Buffer
+	err := Usage(mg, &Specification{}, &buf)
+
+	assert.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, "crash.count")
+	assert.Contains(t, out, "true")
+	assert.Contains(t, out, "handle")
+	assert.Contains(t, out, "zero.cool")
+}
+
+func TestUsageNestedStruct(t *testing.T) {
+	mg := &MockGetter{Values: make(map[string]string)}
+
+	var buf bytes.Buffer
+	err := Usage(mg, &SpecificationWithNested{}, &buf)
+
+	assert.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, "db.host")
+	assert.Contains(t, out, "db.port")
+}
+
+func TestUsageInvalidSpec(t *testing.T) {
+	mg := &MockGetter{Values: make(map[string]string)}
+
+	var buf bytes.Buffer
+	err := Usage(mg, Specification{}, &buf)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,178 @@
+package tagconfig
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Validator evaluates a single rule parsed out of a validate struct tag
+// (e.g. "min=1" or "oneof=a b c") against a field's already-assigned value.
+// Implementations can wrap go-playground/validator, a custom ruleset, or
+// simply rely on the built-ins DefaultValidator ships with.
+type Validator interface {
+	Validate(rule, fieldName string, value interface{}) error
+}
+
+// DefaultValidator is the Validator Process uses to evaluate validate tags
+// unless overridden with RegisterValidator. It understands the built-in
+// rules min, max, oneof, regex, nonzero, and len.
+var DefaultValidator Validator = builtinValidator{}
+
+// RegisterValidator overrides the Validator Process uses for validate
+// tags.
+func RegisterValidator(v Validator) {
+	DefaultValidator = v
+}
+
+// ValidationErrors aggregates every validation failure produced while
+// processing a spec's validate tags, so callers see all invalid fields at
+// once instead of only the first.
+type ValidationErrors []error
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+type builtinValidator struct{}
+
+func (builtinValidator) Validate(rule, fieldName string, value interface{}) error {
+	value = derefValidateValue(value)
+
+	name, arg := splitRule(rule)
+	switch name {
+	case "nonzero":
+		return validateNonzero(fieldName, value)
+	case "min":
+		return validateMin(fieldName, value, arg)
+	case "max":
+		return validateMax(fieldName, value, arg)
+	case "oneof":
+		return validateOneof(fieldName, value, arg)
+	case "regex":
+		return validateRegex(fieldName, value, arg)
+	case "len":
+		return validateLen(fieldName, value, arg)
+	default:
+		return fmt.Errorf("tagconfig: unknown validation rule %q for field %s", name, fieldName)
+	}
+}
+
+// derefValidateValue unwraps a non-nil pointer so the built-in rules
+// validate the pointed-to value (e.g. a *int field's int) rather than the
+// pointer itself. A nil pointer is left as-is, so nonzero still reports it
+// as the zero value rather than panicking on a nil deref.
+func derefValidateValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		return v.Elem().Interface()
+	}
+	return value
+}
+
+func splitRule(rule string) (name, arg string) {
+	parts := strings.SplitN(rule, "=", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return parts[0], ""
+}
+
+func validateNonzero(fieldName string, value interface{}) error {
+	if reflect.ValueOf(value).IsZero() {
+		return fmt.Errorf("field %s must not be the zero value", fieldName)
+	}
+	return nil
+}
+
+func validateMin(fieldName string, value interface{}, arg string) error {
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return fmt.Errorf("tagconfig: invalid min bound %q for field %s", arg, fieldName)
+	}
+
+	n, ok := numericValue(value)
+	if !ok {
+		return fmt.Errorf("tagconfig: min requires a numeric field, got %T for field %s", value, fieldName)
+	}
+	if n < bound {
+		return fmt.Errorf("field %s value %v is below minimum %v", fieldName, value, bound)
+	}
+	return nil
+}
+
+func validateMax(fieldName string, value interface{}, arg string) error {
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return fmt.Errorf("tagconfig: invalid max bound %q for field %s", arg, fieldName)
+	}
+
+	n, ok := numericValue(value)
+	if !ok {
+		return fmt.Errorf("tagconfig: max requires a numeric field, got %T for field %s", value, fieldName)
+	}
+	if n > bound {
+		return fmt.Errorf("field %s value %v is above maximum %v", fieldName, value, bound)
+	}
+	return nil
+}
+
+func validateOneof(fieldName string, value interface{}, arg string) error {
+	allowed := strings.Fields(arg)
+	got := fmt.Sprintf("%v", value)
+	for _, a := range allowed {
+		if a == got {
+			return nil
+		}
+	}
+	return fmt.Errorf("field %s value %q is not one of %v", fieldName, got, allowed)
+}
+
+func validateRegex(fieldName string, value interface{}, arg string) error {
+	re, err := regexp.Compile(arg)
+	if err != nil {
+		return fmt.Errorf("tagconfig: invalid regex %q for field %s", arg, fieldName)
+	}
+	if !re.MatchString(fmt.Sprintf("%v", value)) {
+		return fmt.Errorf("field %s value %q does not match pattern %q", fieldName, value, arg)
+	}
+	return nil
+}
+
+func validateLen(fieldName string, value interface{}, arg string) error {
+	want, err := strconv.Atoi(arg)
+	if err != nil {
+		return fmt.Errorf("tagconfig: invalid len bound %q for field %s", arg, fieldName)
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		if v.Len() != want {
+			return fmt.Errorf("field %s has length %d, want %d", fieldName, v.Len(), want)
+		}
+		return nil
+	default:
+		return fmt.Errorf("tagconfig: len requires a string, slice, array, or map, got %T for field %s", value, fieldName)
+	}
+}
+
+func numericValue(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,70 @@
+package tagconfig_test
+
+import (
+	"testing"
+
+	. "github.com/Experticity/tagconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+type SpecificationWithValidation struct {
+	Port  int    `emaNgaT:"port" validate:"min=1,max=65535"`
+	Level string `emaNgaT:"level" validate:"oneof=debug info warn error"`
+}
+
+type SpecificationWithPointerValidation struct {
+	Port *int `emaNgaT:"port" validate:"min=1,max=65535"`
+}
+
+func TestValidateSuccess(t *testing.T) {
+	spec := &SpecificationWithValidation{}
+
+	mg := &MockGetter{Values: map[string]string{
+		"port":  "8080",
+		"level": "info",
+	}}
+
+	err := Process(mg, spec)
+
+	assert.NoError(t, err)
+}
+
+func TestValidateAggregatesFailures(t *testing.T) {
+	spec := &SpecificationWithValidation{}
+
+	mg := &MockGetter{Values: map[string]string{
+		"port":  "99999",
+		"level": "verbose",
+	}}
+
+	err := Process(mg, spec)
+
+	assert.Error(t, err)
+
+	valErrs, ok := err.(ValidationErrors)
+	assert.True(t, ok)
+	assert.Len(t, valErrs, 2)
+}
+
+func TestValidatePointerField(t *testing.T) {
+	spec := &SpecificationWithPointerValidation{}
+
+	mg := &MockGetter{Values: map[string]string{"port": "8080"}}
+
+	err := Process(mg, spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, *spec.Port)
+}
+
+func TestValidatePointerFieldOutOfRange(t *testing.T) {
+	spec := &SpecificationWithPointerValidation{}
+
+	mg := &MockGetter{Values: map[string]string{"port": "99999"}}
+
+	err := Process(mg, spec)
+
+	assert.Error(t, err)
+	_, ok := err.(ValidationErrors)
+	assert.True(t, ok)
+}